@@ -0,0 +1,99 @@
+package quobyte
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestSnapshotDir(t *testing.T) {
+	t.Run("unmounted volume is an error", func(t *testing.T) {
+		vol := &QuobyteVolume{Name: "myvolume"}
+		if _, err := snapshotDir(vol, "snap1"); err == nil {
+			t.Fatal("snapshotDir() = nil error, want an error for an unmounted volume")
+		}
+	})
+
+	t.Run("joins the staging path, SNAPSHOTS_DIR and snapshot name", func(t *testing.T) {
+		vol := &QuobyteVolume{Name: "myvolume", StagingPath: "/mnt/quobyte/myvolume"}
+		got, err := snapshotDir(vol, "snap1")
+		if err != nil {
+			t.Fatalf("snapshotDir() returned unexpected error: %v", err)
+		}
+		want := filepath.Join("/mnt/quobyte/myvolume", SNAPSHOTS_DIR, "snap1")
+		if got != want {
+			t.Errorf("snapshotDir() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestStreamSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatalf("failed to create fixture subdirectory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("world"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := streamSnapshot(dir, &buf); err != nil {
+		t.Fatalf("streamSnapshot() returned unexpected error: %v", err)
+	}
+
+	gzr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("output is not valid gzip: %v", err)
+	}
+	defer gzr.Close()
+
+	contents := map[string]string{}
+	var names []string
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar entry: %v", err)
+		}
+		names = append(names, hdr.Name)
+		if hdr.Typeflag == tar.TypeReg {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("failed to read tar entry %v: %v", hdr.Name, err)
+			}
+			contents[hdr.Name] = string(data)
+		}
+	}
+	sort.Strings(names)
+
+	wantNames := []string{"a.txt", "sub", filepath.Join("sub", "b.txt")}
+	sort.Strings(wantNames)
+	if len(names) != len(wantNames) {
+		t.Fatalf("archive entries = %v, want %v", names, wantNames)
+	}
+	for i := range names {
+		if names[i] != wantNames[i] {
+			t.Errorf("archive entries = %v, want %v", names, wantNames)
+			break
+		}
+	}
+
+	if got := contents["a.txt"]; got != "hello" {
+		t.Errorf("a.txt contents = %q, want %q", got, "hello")
+	}
+	if got := contents[filepath.Join("sub", "b.txt")]; got != "world" {
+		t.Errorf("sub/b.txt contents = %q, want %q", got, "world")
+	}
+}
@@ -0,0 +1,221 @@
+package quobyte
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	quobyte_api "github.com/quobyte/api"
+)
+
+const (
+	apiMaxRetries     = 5
+	apiInitialBackoff = 500 * time.Millisecond
+	apiMaxBackoff     = 30 * time.Second
+)
+
+// apiCredentials is the JSON shape expected in a QUOBYTE_API_CREDENTIALS_FILE,
+// keeping secrets out of the driver's command line flags.
+type apiCredentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+type clientConfig struct {
+	urls               []string
+	user               string
+	password           string
+	caFile             string
+	insecureSkipVerify bool
+}
+
+// client fronts one quobyte_api.QuobyteClient per configured registry API
+// URL and rotates between them with exponential backoff on failure, the way
+// the Kubernetes Quobyte volume plugin fails over across its API servers.
+type client struct {
+	backends []*quobyte_api.QuobyteClient
+
+	// maxRetries/initialBackoff/maxBackoff configure call's rotation below.
+	// newClient always sets these to the apiMaxRetries/apiInitialBackoff/
+	// apiMaxBackoff defaults; they're broken out as fields only so tests can
+	// exercise the retry/backoff state machine without waiting on it.
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func newClient(cfg clientConfig) (*client, error) {
+	if len(cfg.urls) == 0 {
+		return nil, fmt.Errorf("at least one Quobyte API URL is required")
+	}
+
+	tlsConfig, err := buildTLSConfig(cfg.caFile, cfg.insecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]*quobyte_api.QuobyteClient, 0, len(cfg.urls))
+	for _, url := range cfg.urls {
+		qc := quobyte_api.NewQuobyteClient(url, cfg.user, cfg.password)
+		if tlsConfig != nil && strings.HasPrefix(url, "https://") {
+			qc.SetTransport(&http.Transport{TLSClientConfig: tlsConfig})
+		}
+		backends = append(backends, qc)
+	}
+
+	return &client{
+		backends:       backends,
+		maxRetries:     apiMaxRetries,
+		initialBackoff: apiInitialBackoff,
+		maxBackoff:     apiMaxBackoff,
+	}, nil
+}
+
+func buildTLSConfig(caFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	if caFile == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if caFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %v", caFile)
+	}
+	tlsConfig.RootCAs = pool
+
+	return tlsConfig, nil
+}
+
+// loadCredentials reads user/password from credentialsFile if given,
+// otherwise falls back to the values already resolved from config/defaults.
+func loadCredentials(credentialsFile, fallbackUser, fallbackPassword string) (string, string, error) {
+	if credentialsFile == "" {
+		return fallbackUser, fallbackPassword, nil
+	}
+
+	data, err := ioutil.ReadFile(credentialsFile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %v: %v", credentialsFile, err)
+	}
+
+	var creds apiCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", "", fmt.Errorf("failed to parse %v: %v", credentialsFile, err)
+	}
+
+	return creds.User, creds.Password, nil
+}
+
+// isRetryableAPIError reports whether err looks like a transport/connectivity
+// failure (the registry is unreachable, timed out, refused the connection,
+// ...) as opposed to an application-level error the Quobyte API itself
+// returned (bad request, name already exists, not found, ...). Rotating to
+// another registry and retrying with backoff only makes sense for the
+// former: an application error will fail identically on every registry, so
+// retrying it just delays surfacing a permanent failure to the caller.
+func isRetryableAPIError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// call runs fn against each backend in turn, retrying the whole rotation
+// with exponential backoff until one succeeds, a non-retryable error is
+// returned, or the retries are exhausted.
+//
+// Every call site holds Driver.mutex for the duration of its call into the
+// client, so in the worst case (all registries down) this blocks every other
+// Volume/Snapshot/Backup operation on the driver for up to apiMaxRetries *
+// apiMaxBackoff. That's deliberate: the alternative is dropping the driver
+// lock around the network round-trip, which opens a window for concurrent
+// callers to race on the same on-disk volume/snapshot config while this
+// call is still in flight. If transient Quobyte outages of that length turn
+// out to be common in practice, revisit by having callers release d.mutex
+// before invoking client.call and re-acquire it only to persist the result.
+func (c *client) call(fn func(*quobyte_api.QuobyteClient) error) error {
+	var err error
+	backoff := c.initialBackoff
+	for attempt := 0; attempt < c.maxRetries; attempt++ {
+		for _, backend := range c.backends {
+			if err = fn(backend); err == nil {
+				return nil
+			}
+			if !isRetryableAPIError(err) {
+				return err
+			}
+			log.Warnf("Quobyte API call failed, trying next registry: %v", err)
+		}
+
+		time.Sleep(backoff)
+		if backoff < c.maxBackoff {
+			backoff *= 2
+			if backoff > c.maxBackoff {
+				backoff = c.maxBackoff
+			}
+		}
+	}
+	return err
+}
+
+func (c *client) CreateVolume(req *quobyte_api.CreateVolumeRequest) (string, error) {
+	var id string
+	err := c.call(func(qc *quobyte_api.QuobyteClient) error {
+		var err error
+		id, err = qc.CreateVolume(req)
+		return err
+	})
+	return id, err
+}
+
+func (c *client) DeleteVolume(id string) error {
+	return c.call(func(qc *quobyte_api.QuobyteClient) error {
+		return qc.DeleteVolume(id)
+	})
+}
+
+func (c *client) SetQuota(req *quobyte_api.SetQuotaRequest) (string, error) {
+	var id string
+	err := c.call(func(qc *quobyte_api.QuobyteClient) error {
+		var err error
+		id, err = qc.SetQuota(req)
+		return err
+	})
+	return id, err
+}
+
+func (c *client) DeleteQuota(id string) error {
+	return c.call(func(qc *quobyte_api.QuobyteClient) error {
+		return qc.DeleteQuota(id)
+	})
+}
+
+func (c *client) CreateSnapshot(volumeID, name string) error {
+	return c.call(func(qc *quobyte_api.QuobyteClient) error {
+		return qc.CreateSnapshot(volumeID, name)
+	})
+}
+
+func (c *client) DeleteSnapshot(volumeID, name string) error {
+	return c.call(func(qc *quobyte_api.QuobyteClient) error {
+		return qc.DeleteSnapshot(volumeID, name)
+	})
+}
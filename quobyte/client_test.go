@@ -0,0 +1,142 @@
+package quobyte
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	quobyte_api "github.com/quobyte/api"
+)
+
+// testClient builds a client with tiny backoffs so the retry tests below
+// don't actually wait on apiInitialBackoff/apiMaxBackoff. The backends slice
+// is only ever used as an iteration count by call -- the fn passed in each
+// test never dereferences the *quobyte_api.QuobyteClient it's given.
+func testClient(numBackends, maxRetries int) *client {
+	return &client{
+		backends:       make([]*quobyte_api.QuobyteClient, numBackends),
+		maxRetries:     maxRetries,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     time.Millisecond,
+	}
+}
+
+// connErr builds a retryable, connection-refused-style error the way the
+// underlying HTTP client would surface one, so isRetryableAPIError treats it
+// as a transport failure rather than an application error from the API.
+func connErr() error {
+	return &url.Error{Op: "Post", URL: "https://registry/api", Err: errors.New("connection refused")}
+}
+
+func TestClientCallSucceedsOnFirstBackend(t *testing.T) {
+	c := testClient(3, 5)
+
+	var calls int
+	err := c.call(func(*quobyte_api.QuobyteClient) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("call() returned unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (should stop at the first successful backend)", calls)
+	}
+}
+
+func TestClientCallFailsOverToNextBackend(t *testing.T) {
+	c := testClient(3, 5)
+
+	var calls int
+	err := c.call(func(*quobyte_api.QuobyteClient) error {
+		calls++
+		if calls < 3 {
+			return connErr()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("call() returned unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (should succeed on the third registry)", calls)
+	}
+}
+
+func TestClientCallRetriesRotationAfterBackoff(t *testing.T) {
+	c := testClient(2, 5)
+
+	var calls int
+	err := c.call(func(*quobyte_api.QuobyteClient) error {
+		calls++
+		// Fail through every backend on the first two rotations, then
+		// succeed on the first backend of the third rotation.
+		if calls <= 4 {
+			return connErr()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("call() returned unexpected error: %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("calls = %d, want 5 (two failed rotations of 2, then a success)", calls)
+	}
+}
+
+func TestClientCallExhaustsRetries(t *testing.T) {
+	c := testClient(2, 3)
+
+	var calls int
+	var lastErr error
+	err := c.call(func(*quobyte_api.QuobyteClient) error {
+		calls++
+		lastErr = connErr()
+		return lastErr
+	})
+
+	if err != lastErr {
+		t.Fatalf("call() error = %v, want %v", err, lastErr)
+	}
+	if want := c.maxRetries * len(c.backends); calls != want {
+		t.Errorf("calls = %d, want %d (every backend tried on every retry)", calls, want)
+	}
+}
+
+func TestClientCallFailsFastOnAPIError(t *testing.T) {
+	c := testClient(3, 5)
+
+	wantErr := errors.New("volume already exists")
+	var calls int
+	err := c.call(func(*quobyte_api.QuobyteClient) error {
+		calls++
+		return wantErr
+	})
+
+	if err != wantErr {
+		t.Fatalf("call() error = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (an application error shouldn't be retried across registries)", calls)
+	}
+}
+
+func TestIsRetryableAPIError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"application error", errors.New("volume already exists"), false},
+		{"url.Error from a failed HTTP round-trip", connErr(), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetryableAPIError(c.err); got != c.want {
+				t.Errorf("isRetryableAPIError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
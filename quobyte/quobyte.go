@@ -1,16 +1,22 @@
 package quobyte
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/Sirupsen/logrus"
 	quobyte_api "github.com/quobyte/api"
+	"github.com/rancher/convoy/objectstore"
 	"github.com/rancher/convoy/util"
 
 	. "github.com/rancher/convoy/convoydriver"
@@ -20,21 +26,42 @@ const (
 	DRIVER_NAME        = "quobyte"
 	DRIVER_CONFIG_FILE = "quobyte.cfg"
 
-	VOLUME_CFG_PREFIX = "volume_"
-	CFG_PREFIX        = DRIVER_NAME + "_"
-	CFG_POSTFIX       = ".json"
+	VOLUME_CFG_PREFIX   = "volume_"
+	SNAPSHOT_CFG_PREFIX = "snapshot_"
+	BACKUP_CFG_PREFIX   = "backup_"
+	CFG_PREFIX          = DRIVER_NAME + "_"
+	CFG_POSTFIX         = ".json"
 
 	MOUNTS_DIR = "mounts"
 
-	QUOBYTE_API_URL      = "quobyte.apiurl"
-	QUOBYTE_API_USER     = "quobyte.apiuser"
-	QUOBYTE_API_PASSWORD = "quobyte.apipassword"
+	// SNAPSHOTS_DIR is the directory Quobyte exposes under a volume's mount
+	// point containing a read-only tree per snapshot, used to stream backups.
+	SNAPSHOTS_DIR = ".snapshots"
+
+	QUOBYTE_API_URL                  = "quobyte.apiurl"
+	QUOBYTE_API_USER                 = "quobyte.apiuser"
+	QUOBYTE_API_PASSWORD             = "quobyte.apipassword"
+	QUOBYTE_API_CA_FILE              = "quobyte.apicafile"
+	QUOBYTE_API_INSECURE_SKIP_VERIFY = "quobyte.apiinsecureskipverify"
+	QUOBYTE_API_CREDENTIALS_FILE     = "quobyte.apicredentialsfile"
 
 	QUOBYTE_REGISTRIES            = "quobyte.registries"
 	QUOBYTE_DEFAULT_USER          = "quobyte.defaultuser"
 	QUOBYTE_DEFAULT_GROUP         = "quobyte.defaultgroup"
 	QUOBYTE_DEFAULT_VOLUME_CONFIG = "quobyte.defaultvolumeconfig"
-	//TODO -> Tenant?
+	QUOBYTE_CREATE_QUOTA          = "quobyte.createquota"
+	QUOBYTE_DEFAULT_TENANT        = "quobyte.defaulttenant"
+	QUOBYTE_DEFAULT_VOLUME_SIZE   = "quobyte.defaultvolumesize"
+
+	OPT_VOLUME_TENANT             = "quobyte.tenant"
+	OPT_VOLUME_USER               = "quobyte.user"
+	OPT_VOLUME_GROUP              = "quobyte.group"
+	OPT_VOLUME_CONFIGURATION_NAME = "quobyte.configurationName"
+
+	OPT_VOLUME_MOUNT_ID = "MountID"
+
+	QUOTA_CONSUMER_VOLUME          = "VOLUME"
+	QUOTA_LIMIT_LOGICAL_DISK_SPACE = "LOGICAL_DISK_SPACE"
 )
 
 var (
@@ -43,7 +70,7 @@ var (
 
 type Driver struct {
 	mutex  *sync.RWMutex
-	client *quobyte_api.QuobyteClient
+	client *client
 	Device
 }
 
@@ -62,6 +89,8 @@ type Device struct {
 	Group             string
 	VolumeConfig      string
 	DefaultVolumeSize int64
+	CreateQuota       bool
+	DefaultTenant     string
 }
 
 func (dev *Device) ConfigFile() (string, error) {
@@ -72,14 +101,33 @@ func (dev *Device) ConfigFile() (string, error) {
 }
 
 type QuobyteVolume struct {
-	Name       string
-	ID         string
-	MountPoint string
-	configPath string
-	User       string
-	Group      string
-	Device     string
-	Config     string
+	Name              string
+	ID                string
+	configPath        string
+	User              string
+	Group             string
+	Device            string
+	Registries        string
+	Tenant            string
+	// ConfigurationName is stored under the pre-tenant-support JSON key
+	// "Config" so volumes created by an older daemon still deserialize into
+	// it correctly instead of silently coming back empty.
+	ConfigurationName string `json:"Config"`
+	Size              int64
+	QuotaID           string
+
+	// StagingPath is the single global mount of the Quobyte filesystem for
+	// this volume, analogous to a CSI NodeStageVolume target. Every
+	// requested target path is then bind-mounted from it, so repeated
+	// mounts of a shared volume don't each pay for a fresh "mount -t
+	// quobyte" against the registries.
+	StagingPath string
+
+	// BindMounts maps each bind-mounted target path to the set of callers
+	// (keyed by their MountID, or a freshly generated UUID per Mount call
+	// when none is given) currently holding it, mirroring how the Docker volume store
+	// and Podman's volume plugin reference-count Mount/Unmount pairs.
+	BindMounts map[string]map[string]bool
 }
 
 func (v *QuobyteVolume) ConfigFile() (string, error) {
@@ -98,17 +146,126 @@ func (v *QuobyteVolume) GetMountOpts() []string {
 }
 
 func (v *QuobyteVolume) GetDevice() (string, error) {
-	return v.Device, nil
+	if v.Tenant != "" {
+		return fmt.Sprintf("%s/%s|%s", v.Registries, v.Tenant, v.Device), nil
+	}
+	return fmt.Sprintf("%s/%s", v.Registries, v.Device), nil
 }
 
 func (v *QuobyteVolume) GenerateDefaultMountPoint() string {
 	return filepath.Join(v.configPath, MOUNTS_DIR, v.Name)
 }
 
+// totalRefs returns the number of bind-mounted target paths still held by
+// at least one caller. The staging mount can be released once this is zero.
+func (v *QuobyteVolume) totalRefs() int {
+	return len(v.BindMounts)
+}
+
+// addBindRef records mountID as a holder of target, creating the bind entry
+// if this is the first caller for that target. It reports whether target is
+// newly bound, so the caller knows whether it still needs to bindMount it.
+//
+// Callers that don't supply a MountID (anonymous callers, the common case
+// since nothing upstream of this driver wires one through yet) must still
+// get a distinct holder per Mount call: two anonymous mounts of the same
+// target are two independent holds, and collapsing them onto one shared key
+// (e.g. target itself) would make the second Mount a no-op and let the
+// first caller's Umount tear down a bind the second caller still needs.
+func (v *QuobyteVolume) addBindRef(target, mountID string) bool {
+	if v.BindMounts == nil {
+		v.BindMounts = map[string]map[string]bool{}
+	}
+
+	refs, bound := v.BindMounts[target]
+	if !bound {
+		refs = map[string]bool{}
+		v.BindMounts[target] = refs
+	}
+
+	key := mountID
+	if key == "" {
+		key = util.UUID()
+	}
+	refs[key] = true
+
+	return !bound
+}
+
+// removeBindRef releases mountID's hold on target. It reports whether this
+// call released the last holder of target, so the caller knows whether it
+// should bindUnmount it; for a target that isn't bound at all it reports
+// false.
+//
+// An anonymous Umount (empty mountID) can't reproduce the unique key its
+// matching anonymous Mount generated, but it doesn't need to: every
+// anonymous holder of the same target is interchangeable, so releasing any
+// one of them is exactly as correct as releasing "the" one the caller meant.
+func (v *QuobyteVolume) removeBindRef(target, mountID string) bool {
+	refs, bound := v.BindMounts[target]
+	if !bound {
+		return false
+	}
+
+	if mountID != "" {
+		delete(refs, mountID)
+	} else {
+		for key := range refs {
+			delete(refs, key)
+			break
+		}
+	}
+
+	if len(refs) > 0 {
+		return false
+	}
+
+	delete(v.BindMounts, target)
+	return true
+}
+
 func (device *Device) listVolumeNames() ([]string, error) {
 	return util.ListConfigIDs(device.Root, CFG_PREFIX+VOLUME_CFG_PREFIX, CFG_POSTFIX)
 }
 
+type QuobyteSnapshot struct {
+	Name       string
+	VolumeName string
+	VolumeID   string
+	configPath string
+}
+
+func (s *QuobyteSnapshot) ConfigFile() (string, error) {
+	if s.Name == "" {
+		return "", errors.New("empty snapshot name")
+	}
+	if s.configPath == "" {
+		return "", errors.New("empty config path")
+	}
+
+	return filepath.Join(s.configPath, CFG_PREFIX+SNAPSHOT_CFG_PREFIX+s.Name+CFG_POSTFIX), nil
+}
+
+type QuobyteBackup struct {
+	Name         string
+	VolumeName   string
+	VolumeID     string
+	SnapshotName string
+	URL          string
+	configPath   string
+}
+
+func (b *QuobyteBackup) ConfigFile() (string, error) {
+	if b.Name == "" {
+		return "", errors.New("empty backup name")
+	}
+	if b.configPath == "" {
+		return "", errors.New("empty config path")
+	}
+
+	return filepath.Join(b.configPath, CFG_PREFIX+BACKUP_CFG_PREFIX+b.Name+CFG_POSTFIX), nil
+}
+
 func Init(root string, config map[string]string) (ConvoyDriver, error) {
 	dev := &Device{
 		Root: root,
@@ -163,19 +320,61 @@ func Init(root string, config map[string]string) (ConvoyDriver, error) {
 			config[QUOBYTE_DEFAULT_VOLUME_CONFIG] = "BASE"
 		}
 
+		createQuota := true
+		if value, exists := config[QUOBYTE_CREATE_QUOTA]; exists {
+			createQuota, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for %v: %v", QUOBYTE_CREATE_QUOTA, err)
+			}
+		}
+
+		var defaultVolumeSize int64
+		if value, exists := config[QUOBYTE_DEFAULT_VOLUME_SIZE]; exists && value != "" {
+			defaultVolumeSize, err = util.ParseSize(value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for %v: %v", QUOBYTE_DEFAULT_VOLUME_SIZE, err)
+			}
+		}
+
 		dev = &Device{
-			Root:         root,
-			Registries:   registryList,
-			User:         config[QUOBYTE_DEFAULT_USER],
-			Group:        config[QUOBYTE_DEFAULT_GROUP],
-			VolumeConfig: config[QUOBYTE_DEFAULT_VOLUME_CONFIG],
+			Root:              root,
+			Registries:        registryList,
+			User:              config[QUOBYTE_DEFAULT_USER],
+			Group:             config[QUOBYTE_DEFAULT_GROUP],
+			VolumeConfig:      config[QUOBYTE_DEFAULT_VOLUME_CONFIG],
+			CreateQuota:       createQuota,
+			DefaultTenant:     config[QUOBYTE_DEFAULT_TENANT],
+			DefaultVolumeSize: defaultVolumeSize,
+		}
+	}
+
+	user, password, err := loadCredentials(config[QUOBYTE_API_CREDENTIALS_FILE], config[QUOBYTE_API_USER], config[QUOBYTE_API_PASSWORD])
+	if err != nil {
+		return nil, err
+	}
+
+	var insecureSkipVerify bool
+	if value, exists := config[QUOBYTE_API_INSECURE_SKIP_VERIFY]; exists {
+		insecureSkipVerify, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for %v: %v", QUOBYTE_API_INSECURE_SKIP_VERIFY, err)
 		}
 	}
+	apiClient, err := newClient(clientConfig{
+		urls:               strings.Split(config[QUOBYTE_API_URL], ","),
+		user:               user,
+		password:           password,
+		caFile:             config[QUOBYTE_API_CA_FILE],
+		insecureSkipVerify: insecureSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	driver := &Driver{
 		mutex:  &sync.RWMutex{},
 		Device: *dev,
-		client: quobyte_api.NewQuobyteClient(config[QUOBYTE_API_URL], config[QUOBYTE_API_USER], config[QUOBYTE_API_PASSWORD]),
+		client: apiClient,
 	}
 
 	if err := driver.remountVolumes(); err != nil {
@@ -190,6 +389,7 @@ func (d *Driver) Info() (map[string]string, error) {
 		"Registries":   fmt.Sprintf("%v", d.Registries),
 		"User":         d.User,
 		"VolumeConfig": d.VolumeConfig,
+		"CreateQuota":  strconv.FormatBool(d.CreateQuota),
 	}, nil
 }
 
@@ -204,6 +404,20 @@ func (d *Driver) blankVolume(name string) *QuobyteVolume {
 	}
 }
 
+func (d *Driver) blankSnapshot(name string) *QuobyteSnapshot {
+	return &QuobyteSnapshot{
+		configPath: d.Root,
+		Name:       name,
+	}
+}
+
+func (d *Driver) blankBackup(name string) *QuobyteBackup {
+	return &QuobyteBackup{
+		configPath: d.Root,
+		Name:       name,
+	}
+}
+
 func (d *Driver) remountVolumes() error {
 	volumes, err := util.ListConfigIDs(d.Root, CFG_PREFIX+VOLUME_CFG_PREFIX, CFG_POSTFIX)
 	if err != nil {
@@ -215,15 +429,35 @@ func (d *Driver) remountVolumes() error {
 		if err := util.ObjectLoad(vol); err != nil {
 			return err
 		}
-		if vol.MountPoint == "" {
+		if vol.StagingPath == "" {
 			continue
 		}
 
-		req := Request{
-			Name:    id,
-			Options: map[string]string{},
+		// The staging mount doesn't survive a daemon restart (it depends on
+		// our own Quobyte client), but the bind mounts into already-running
+		// containers do. Restage first, then rebind every previously known
+		// target onto the fresh staging mount.
+		targets := make([]string, 0, len(vol.BindMounts))
+		for target := range vol.BindMounts {
+			targets = append(targets, target)
+		}
+
+		stagingPath, err := util.VolumeMount(vol, vol.StagingPath, false)
+		if err != nil {
+			return err
+		}
+		vol.StagingPath = stagingPath
+
+		for _, target := range targets {
+			if target == stagingPath {
+				continue
+			}
+			if err := bindMount(stagingPath, target); err != nil {
+				return err
+			}
 		}
-		if _, err := d.MountVolume(req); err != nil {
+
+		if err := util.ObjectSave(vol); err != nil {
 			return err
 		}
 	}
@@ -231,9 +465,51 @@ func (d *Driver) remountVolumes() error {
 	return nil
 }
 
+// bindMount bind-mounts source onto target, creating target if necessary.
+func bindMount(source, target string) error {
+	if err := util.MkdirIfNotExists(target); err != nil {
+		return err
+	}
+	return syscall.Mount(source, target, "", syscall.MS_BIND, "")
+}
+
+// bindUnmount releases a bind mount created by bindMount.
+func bindUnmount(target string) error {
+	return syscall.Unmount(target, 0)
+}
+
+const bytesInGiB = 1 << 30
+
+// roundUpGiB rounds size up to the next whole GiB, the granularity Quobyte
+// quotas are expressed in, mirroring the Kubernetes Quobyte provisioner.
+func roundUpGiB(size int64) int64 {
+	if size <= 0 {
+		return 0
+	}
+	gib := (size + bytesInGiB - 1) / bytesInGiB
+	return gib * bytesInGiB
+}
+
+// optOrDefault returns opts[key] if it was set to a non-empty value,
+// falling back to def (typically a Device-level default) otherwise.
+func optOrDefault(opts map[string]string, key, def string) string {
+	if value, exists := opts[key]; exists && value != "" {
+		return value
+	}
+	return def
+}
+
 func (d *Driver) getSize(opts map[string]string, defaultVolumeSize int64) (int64, error) {
-	//TODO Quobyte Volume has no size
-	return 0, nil
+	sizeStr := opts[OPT_SIZE]
+	if sizeStr == "" {
+		return roundUpGiB(defaultVolumeSize), nil
+	}
+
+	size, err := util.ParseSize(sizeStr)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid size %v: %v", sizeStr, err)
+	}
+	return roundUpGiB(size), nil
 }
 
 func (d *Driver) CreateVolume(req Request) error {
@@ -250,11 +526,22 @@ func (d *Driver) CreateVolume(req Request) error {
 		return fmt.Errorf("volume %s already exists", id)
 	}
 
+	size, err := d.getSize(req.Options, d.DefaultVolumeSize)
+	if err != nil {
+		return err
+	}
+
+	tenant := optOrDefault(req.Options, OPT_VOLUME_TENANT, d.DefaultTenant)
+	user := optOrDefault(req.Options, OPT_VOLUME_USER, d.User)
+	group := optOrDefault(req.Options, OPT_VOLUME_GROUP, d.Group)
+	configName := optOrDefault(req.Options, OPT_VOLUME_CONFIGURATION_NAME, d.VolumeConfig)
+
 	volume_uuid, err := d.client.CreateVolume(&quobyte_api.CreateVolumeRequest{
 		Name:              id,
-		RootUserID:        d.User,
-		RootGroupID:       d.Group,
-		ConfigurationName: d.VolumeConfig,
+		RootUserID:        user,
+		RootGroupID:       group,
+		ConfigurationName: configName,
+		TenantID:          tenant,
 	})
 
 	if err != nil {
@@ -263,11 +550,37 @@ func (d *Driver) CreateVolume(req Request) error {
 
 	vol.Name = id
 	vol.ID = volume_uuid
-	vol.User = d.User
-	vol.Group = d.Group
-	vol.Config = d.VolumeConfig
+	vol.User = user
+	vol.Group = group
+	vol.ConfigurationName = configName
+	vol.Tenant = tenant
+	vol.Registries = d.Registries
 	vol.Device = vol.Name
 
+	if size > 0 && d.CreateQuota {
+		quotaID, err := d.client.SetQuota(&quobyte_api.SetQuotaRequest{
+			Consumer: quobyte_api.QuotaConsumer{
+				Type:       QUOTA_CONSUMER_VOLUME,
+				Identifier: volume_uuid,
+			},
+			Limits: []quobyte_api.QuotaLimit{
+				{
+					Type:  QUOTA_LIMIT_LOGICAL_DISK_SPACE,
+					Value: uint64(size),
+				},
+			},
+		})
+		if err != nil {
+			// Don't leave an orphaned volume behind if quota creation fails.
+			if delErr := d.client.DeleteVolume(volume_uuid); delErr != nil {
+				log.Errorf("Failed to clean up volume %v after quota creation failed: %v", id, delErr)
+			}
+			return fmt.Errorf("Failed to set quota for volume %v: %v", id, err)
+		}
+		vol.QuotaID = quotaID
+		vol.Size = size
+	}
+
 	return util.ObjectSave(vol)
 }
 
@@ -283,13 +596,29 @@ func (d *Driver) DeleteVolume(req Request) error {
 		return err
 	}
 
-	if volume.MountPoint != "" {
+	if volume.StagingPath != "" {
 		return fmt.Errorf("Cannot delete volume %v. It is still mounted", id)
 	}
 
 	referenceOnly, _ := strconv.ParseBool(opts[OPT_REFERENCE_ONLY])
 	if !referenceOnly {
 		log.Debugf("Cleaning up volume %v", id)
+
+		snapshots, err := d.ListSnapshot(map[string]string{OPT_VOLUME_NAME: id})
+		if err != nil {
+			return err
+		}
+		for name := range snapshots {
+			if err := d.deleteSnapshot(name); err != nil {
+				return err
+			}
+		}
+
+		if volume.QuotaID != "" {
+			if err := d.client.DeleteQuota(volume.QuotaID); err != nil {
+				return err
+			}
+		}
 		if err := d.client.DeleteVolume(volume.ID); err != nil {
 			return err
 		}
@@ -308,41 +637,86 @@ func (d *Driver) MountVolume(req Request) (string, error) {
 		return "", err
 	}
 
-	mountPoint, err := util.VolumeMount(vol, opts[OPT_MOUNT_POINT], false)
-	if err != nil {
-		return "", err
+	// Stage: mount the Quobyte filesystem itself exactly once, at a fixed
+	// per-volume path, regardless of how many targets end up bound to it.
+	if vol.StagingPath == "" {
+		stagingPath, err := util.VolumeMount(vol, "", false)
+		if err != nil {
+			return "", err
+		}
+		vol.StagingPath = stagingPath
+	}
+
+	// Publish: bind-mount the staged filesystem into the requested target,
+	// reusing an existing bind if one is already there for this target.
+	target := opts[OPT_MOUNT_POINT]
+	if target == "" {
+		target = vol.StagingPath
+	}
+
+	mountID := opts[OPT_VOLUME_MOUNT_ID]
+
+	if vol.addBindRef(target, mountID) && target != vol.StagingPath {
+		if err := bindMount(vol.StagingPath, target); err != nil {
+			return "", err
+		}
 	}
 
 	if err := util.ObjectSave(vol); err != nil {
 		return "", err
 	}
 
-	return mountPoint, nil
+	return target, nil
 }
 
 func (d *Driver) UmountVolume(req Request) error {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
+	opts := req.Options
+
 	vol := d.blankVolume(req.Name)
 	if err := util.ObjectLoad(vol); err != nil {
 		return err
 	}
 
-	if err := util.VolumeUmount(vol); err != nil {
-		return err
+	target := opts[OPT_MOUNT_POINT]
+	if target == "" {
+		target = vol.StagingPath
+	}
+
+	mountID := opts[OPT_VOLUME_MOUNT_ID]
+
+	if vol.removeBindRef(target, mountID) && target != vol.StagingPath {
+		if err := bindUnmount(target); err != nil {
+			return err
+		}
+	}
+
+	// Only tear down the staging mount once every bind mount derived from
+	// it has been released.
+	if vol.totalRefs() == 0 {
+		if err := util.VolumeUmount(vol); err != nil {
+			return err
+		}
+		vol.StagingPath = ""
 	}
 
 	return util.ObjectSave(vol)
 }
 
+// MountPoint reports the volume's staging mount — the one Quobyte
+// filesystem mount every bind-mounted target is derived from. It does not
+// disambiguate between individual bind targets; callers that requested a
+// specific target via OPT_MOUNT_POINT already have that path back from
+// MountVolume.
 func (d *Driver) MountPoint(req Request) (string, error) {
 	vol := d.blankVolume(req.Name)
 	if err := util.ObjectLoad(vol); err != nil {
 		return "", err
 	}
 
-	return vol.MountPoint, nil
+	return vol.StagingPath, nil
 }
 
 func (d *Driver) GetVolumeInfo(name string) (map[string]string, error) {
@@ -360,11 +734,16 @@ func (d *Driver) GetVolumeInfo(name string) (map[string]string, error) {
 		return nil, err
 	}*/
 
-	//TODO User, Group, Config
 	info := map[string]string{
-		"MountPoint":    vol.MountPoint,
-		"ID":            vol.ID,
-		OPT_VOLUME_NAME: name,
+		"MountPoint":        vol.StagingPath,
+		"ID":                vol.ID,
+		"Size":              strconv.FormatInt(vol.Size, 10),
+		"QuotaID":           vol.QuotaID,
+		"Tenant":            vol.Tenant,
+		"User":              vol.User,
+		"Group":             vol.Group,
+		"ConfigurationName": vol.ConfigurationName,
+		OPT_VOLUME_NAME:     name,
 	}
 	return info, nil
 }
@@ -385,9 +764,285 @@ func (d *Driver) ListVolume(opts map[string]string) (map[string]map[string]strin
 }
 
 func (d *Driver) SnapshotOps() (SnapshotOperations, error) {
-	return nil, fmt.Errorf("Doesn't support snapshot operations")
+	return d, nil
+}
+
+func (d *Driver) CreateSnapshot(req Request) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	id := req.Name
+	volumeName := req.Options[OPT_VOLUME_NAME]
+
+	snap := d.blankSnapshot(id)
+	exists, err := util.ObjectExists(snap)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("snapshot %s already exists", id)
+	}
+
+	vol := d.blankVolume(volumeName)
+	if err := util.ObjectLoad(vol); err != nil {
+		return err
+	}
+
+	if err := d.client.CreateSnapshot(vol.ID, id); err != nil {
+		return err
+	}
+
+	snap.VolumeName = volumeName
+	snap.VolumeID = vol.ID
+
+	return util.ObjectSave(snap)
+}
+
+func (d *Driver) DeleteSnapshot(req Request) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.deleteSnapshot(req.Name)
+}
+
+// deleteSnapshot does the actual work of DeleteSnapshot without taking
+// d.mutex, so callers that already hold it (e.g. DeleteVolume cleaning up a
+// volume's snapshots) can call it directly instead of deadlocking on the
+// non-reentrant lock.
+func (d *Driver) deleteSnapshot(name string) error {
+	snap := d.blankSnapshot(name)
+	if err := util.ObjectLoad(snap); err != nil {
+		return err
+	}
+
+	if err := d.client.DeleteSnapshot(snap.VolumeID, snap.Name); err != nil {
+		return err
+	}
+
+	return util.ObjectDelete(snap)
+}
+
+func (d *Driver) GetSnapshotInfo(id string) (map[string]string, error) {
+	snap := d.blankSnapshot(id)
+	if err := util.ObjectLoad(snap); err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"Name":          snap.Name,
+		OPT_VOLUME_NAME: snap.VolumeName,
+		"VolumeID":      snap.VolumeID,
+	}, nil
+}
+
+func (d *Driver) ListSnapshot(opts map[string]string) (map[string]map[string]string, error) {
+	snapshots, err := util.ListConfigIDs(d.Root, CFG_PREFIX+SNAPSHOT_CFG_PREFIX, CFG_POSTFIX)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeFilter := opts[OPT_VOLUME_NAME]
+	ret := make(map[string]map[string]string)
+	for _, id := range snapshots {
+		info, err := d.GetSnapshotInfo(id)
+		if err != nil {
+			return nil, err
+		}
+		if volumeFilter != "" && info[OPT_VOLUME_NAME] != volumeFilter {
+			continue
+		}
+		ret[id] = info
+	}
+	return ret, nil
 }
 
 func (d *Driver) BackupOps() (BackupOperations, error) {
-	return nil, fmt.Errorf("Doesn't support backup operations")
+	return d, nil
+}
+
+// snapshotDir returns the read-only directory Quobyte exposes for a
+// snapshot taken against vol, rooted at the volume's own mount point.
+func snapshotDir(vol *QuobyteVolume, snapshotName string) (string, error) {
+	if vol.StagingPath == "" {
+		return "", fmt.Errorf("volume %v must be mounted to access its snapshots", vol.Name)
+	}
+	return filepath.Join(vol.StagingPath, SNAPSHOTS_DIR, snapshotName), nil
+}
+
+// streamSnapshot tars and gzips the given snapshot directory tree straight
+// into w, without staging an intermediate archive on disk.
+func streamSnapshot(dir string, w io.Writer) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+func (d *Driver) CreateBackup(snapshotID, volumeID, destURL string, opts map[string]string) (string, error) {
+	// Only the metadata lookups and the final save of the backup record
+	// need the driver lock. The tar/gzip and
+	// object store upload below can run for minutes on a sizeable volume,
+	// and holding d.mutex across that would stall every other Volume/
+	// Snapshot/Backup call on this driver instance for as long as it takes.
+	d.mutex.Lock()
+	snap := d.blankSnapshot(snapshotID)
+	err := util.ObjectLoad(snap)
+	var vol *QuobyteVolume
+	if err == nil {
+		vol = d.blankVolume(snap.VolumeName)
+		err = util.ObjectLoad(vol)
+	}
+	d.mutex.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := snapshotDir(vol, snap.Name)
+	if err != nil {
+		return "", err
+	}
+
+	bsDriver, err := objectstore.GetObjectStoreDriver(destURL)
+	if err != nil {
+		return "", err
+	}
+
+	backupID := util.UUID()
+	backupKey := filepath.Join("quobyte", vol.ID, backupID+".tar.gz")
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(streamSnapshot(dir, writer))
+	}()
+
+	if err := bsDriver.Write(backupKey, reader); err != nil {
+		return "", err
+	}
+
+	backupURL := fmt.Sprintf("%s?backup=%s&volume=%s", destURL, backupID, vol.ID)
+
+	backup := d.blankBackup(backupID)
+	backup.VolumeName = vol.Name
+	backup.VolumeID = vol.ID
+	backup.SnapshotName = snap.Name
+	backup.URL = backupURL
+
+	d.mutex.Lock()
+	err = util.ObjectSave(backup)
+	d.mutex.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	return backupURL, nil
+}
+
+func (d *Driver) DeleteBackup(backupURL string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	backupID, _, err := util.ParseBackupURL(backupURL)
+	if err != nil {
+		return err
+	}
+
+	backup := d.blankBackup(backupID)
+	if err := util.ObjectLoad(backup); err != nil {
+		return err
+	}
+
+	bsDriver, err := objectstore.GetObjectStoreDriver(backupURL)
+	if err != nil {
+		return err
+	}
+	backupKey := filepath.Join("quobyte", backup.VolumeID, backupID+".tar.gz")
+	if err := bsDriver.Remove(backupKey); err != nil {
+		return err
+	}
+
+	return util.ObjectDelete(backup)
+}
+
+func (d *Driver) GetBackupInfo(backupURL string) (map[string]string, error) {
+	backupID, _, err := util.ParseBackupURL(backupURL)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := d.blankBackup(backupID)
+	if err := util.ObjectLoad(backup); err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"BackupURL":     backup.URL,
+		"SnapshotName":  backup.SnapshotName,
+		OPT_VOLUME_NAME: backup.VolumeName,
+		"VolumeID":      backup.VolumeID,
+	}, nil
+}
+
+func (d *Driver) ListBackup(destURL string, opts map[string]string) (map[string]map[string]string, error) {
+	backups, err := util.ListConfigIDs(d.Root, CFG_PREFIX+BACKUP_CFG_PREFIX, CFG_POSTFIX)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeFilter := opts[OPT_VOLUME_NAME]
+	ret := make(map[string]map[string]string)
+	for _, id := range backups {
+		backup := d.blankBackup(id)
+		if err := util.ObjectLoad(backup); err != nil {
+			return nil, err
+		}
+		if volumeFilter != "" && backup.VolumeName != volumeFilter {
+			continue
+		}
+		info, err := d.GetBackupInfo(backup.URL)
+		if err != nil {
+			return nil, err
+		}
+		ret[id] = info
+	}
+	return ret, nil
 }
@@ -0,0 +1,188 @@
+package quobyte
+
+import "testing"
+
+func TestRoundUpGiB(t *testing.T) {
+	cases := []struct {
+		name string
+		size int64
+		want int64
+	}{
+		{"zero", 0, 0},
+		{"negative", -1, 0},
+		{"exactly one GiB", bytesInGiB, bytesInGiB},
+		{"one byte over a GiB rounds up to two", bytesInGiB + 1, 2 * bytesInGiB},
+		{"one byte under a GiB rounds up to one", bytesInGiB - 1, bytesInGiB},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := roundUpGiB(c.size); got != c.want {
+				t.Errorf("roundUpGiB(%d) = %d, want %d", c.size, got, c.want)
+			}
+		})
+	}
+}
+
+func TestGetDevice(t *testing.T) {
+	cases := []struct {
+		name string
+		vol  QuobyteVolume
+		want string
+	}{
+		{
+			name: "no tenant",
+			vol:  QuobyteVolume{Registries: "registry1:7861,registry2:7861", Device: "myvolume"},
+			want: "registry1:7861,registry2:7861/myvolume",
+		},
+		{
+			name: "with tenant",
+			vol:  QuobyteVolume{Registries: "registry1:7861,registry2:7861", Device: "myvolume", Tenant: "mytenant"},
+			want: "registry1:7861,registry2:7861/mytenant|myvolume",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.vol.GetDevice()
+			if err != nil {
+				t.Fatalf("GetDevice() returned unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("GetDevice() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBindRefCounting(t *testing.T) {
+	vol := &QuobyteVolume{StagingPath: "/mnt/staging"}
+
+	if got := vol.totalRefs(); got != 0 {
+		t.Fatalf("totalRefs() = %d, want 0 on a fresh volume", got)
+	}
+
+	// First caller for a target: a new bind mount is required.
+	if isNew := vol.addBindRef("/mnt/a", "container-1"); !isNew {
+		t.Fatalf("addBindRef() = false, want true for the first ref on /mnt/a")
+	}
+	if got := vol.totalRefs(); got != 1 {
+		t.Fatalf("totalRefs() = %d, want 1 after binding /mnt/a once", got)
+	}
+
+	// Second caller for the same target: the existing bind is reused.
+	if isNew := vol.addBindRef("/mnt/a", "container-2"); isNew {
+		t.Fatalf("addBindRef() = true, want false when /mnt/a already has a holder")
+	}
+	if got := vol.totalRefs(); got != 1 {
+		t.Fatalf("totalRefs() = %d, want 1 after a second ref on the same target", got)
+	}
+
+	// A distinct target is tracked independently.
+	if isNew := vol.addBindRef("/mnt/b", "container-3"); !isNew {
+		t.Fatalf("addBindRef() = false, want true for the first ref on /mnt/b")
+	}
+	if got := vol.totalRefs(); got != 2 {
+		t.Fatalf("totalRefs() = %d, want 2 with two distinct targets bound", got)
+	}
+
+	// Releasing one of two holders on /mnt/a must not tear down the bind.
+	if released := vol.removeBindRef("/mnt/a", "container-1"); released {
+		t.Fatalf("removeBindRef() = true, want false while /mnt/a still has another holder")
+	}
+	if got := vol.totalRefs(); got != 2 {
+		t.Fatalf("totalRefs() = %d, want 2 after releasing one of two holders", got)
+	}
+
+	// Releasing the last holder on /mnt/a tears it down.
+	if released := vol.removeBindRef("/mnt/a", "container-2"); !released {
+		t.Fatalf("removeBindRef() = false, want true when releasing the last holder of /mnt/a")
+	}
+	if got := vol.totalRefs(); got != 1 {
+		t.Fatalf("totalRefs() = %d, want 1 after /mnt/a is fully released", got)
+	}
+
+	// Releasing an unknown (target, mountID) pair is a no-op, not an error.
+	if released := vol.removeBindRef("/mnt/a", "container-1"); released {
+		t.Fatalf("removeBindRef() = true, want false for an already-released target")
+	}
+
+	if released := vol.removeBindRef("/mnt/b", "container-3"); !released {
+		t.Fatalf("removeBindRef() = false, want true when releasing the last holder of /mnt/b")
+	}
+	if got := vol.totalRefs(); got != 0 {
+		t.Fatalf("totalRefs() = %d, want 0 once every bind target is released", got)
+	}
+}
+
+// TestBindRefCountingAnonymousCallers guards against a regression where two
+// anonymous Mount calls (no MountID, the common case) on the same target
+// collapsed onto one shared holder key, making the second Mount a no-op and
+// letting the first Umount tear down a bind mount the second caller still
+// needed.
+func TestBindRefCountingAnonymousCallers(t *testing.T) {
+	vol := &QuobyteVolume{StagingPath: "/mnt/staging"}
+
+	if isNew := vol.addBindRef("/mnt/a", ""); !isNew {
+		t.Fatalf("addBindRef() = false, want true for the first anonymous ref on /mnt/a")
+	}
+	if isNew := vol.addBindRef("/mnt/a", ""); isNew {
+		t.Fatalf("addBindRef() = true, want false for a second ref reusing an existing bind")
+	}
+	if got := vol.totalRefs(); got != 1 {
+		t.Fatalf("totalRefs() = %d, want 1 with one target bound by two anonymous callers", got)
+	}
+
+	// Releasing one of the two anonymous holders must not tear down the
+	// bind: the second anonymous caller is still relying on it.
+	if released := vol.removeBindRef("/mnt/a", ""); released {
+		t.Fatalf("removeBindRef() = true, want false while a second anonymous caller still holds /mnt/a")
+	}
+	if got := vol.totalRefs(); got != 1 {
+		t.Fatalf("totalRefs() = %d, want 1 after releasing only one of two anonymous holders", got)
+	}
+
+	// Releasing the second (last) anonymous holder tears it down.
+	if released := vol.removeBindRef("/mnt/a", ""); !released {
+		t.Fatalf("removeBindRef() = false, want true when releasing the last anonymous holder of /mnt/a")
+	}
+	if got := vol.totalRefs(); got != 0 {
+		t.Fatalf("totalRefs() = %d, want 0 once both anonymous holders are released", got)
+	}
+}
+
+func TestGetSize(t *testing.T) {
+	d := &Driver{}
+
+	cases := []struct {
+		name              string
+		opts              map[string]string
+		defaultVolumeSize int64
+		want              int64
+		wantErr           bool
+	}{
+		{"no Size option falls back to default", map[string]string{}, 2 * bytesInGiB, 2 * bytesInGiB, false},
+		{"no Size option and no default", map[string]string{}, 0, 0, false},
+		{"Size in whole GiB", map[string]string{OPT_SIZE: "3G"}, 0, 3 * bytesInGiB, false},
+		{"Size rounds up to next GiB", map[string]string{OPT_SIZE: "1"}, 0, bytesInGiB, false},
+		{"invalid Size is an error", map[string]string{OPT_SIZE: "not-a-size"}, 0, 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := d.getSize(c.opts, c.defaultVolumeSize)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("getSize() = %d, nil, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getSize() returned unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("getSize() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}